@@ -0,0 +1,32 @@
+// Package agentparse turns Riot's exported agent markdown into structured
+// Agent data. It exposes a Parser that can read a single file, an
+// io.Reader, or walk a whole directory tree, with the markdown-to-Agent
+// translation itself pluggable via the Renderer interface.
+package agentparse
+
+// Ability represents a single ability with its details
+type Ability struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+}
+
+// Agent represents a Valorant agent with all its information
+type Agent struct {
+	Name      string    `json:"name"`
+	Role      string    `json:"role"`
+	Abilities []Ability `json:"abilities"`
+	Media     []string  `json:"media"`
+	Markdown  string    `json:"markdown"`
+}
+
+// Agents holds all agents, keyed by agent name
+type Agents struct {
+	Agents map[string]Agent `json:"agents"`
+}
+
+// AgentFile represents the structure of the JSON files
+type AgentFile struct {
+	Markdown string                 `json:"markdown"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
@@ -0,0 +1,80 @@
+package agentparse
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// fetchTimeout bounds how long Fetch will wait on a slow or unresponsive
+// image host before giving up, so a single bad CDN request can't hang a
+// serveMedia call indefinitely. A var rather than a const so tests can
+// shrink it.
+var fetchTimeout = 10 * time.Second
+
+// MediaCache fetches remote agent/ability images and caches them on disk,
+// keyed by a hash of their URL, so repeated requests for the same asset
+// never hit Riot's CDN twice.
+type MediaCache struct {
+	dir    string
+	client *http.Client
+}
+
+// NewMediaCache returns a MediaCache backed by dir, creating it if
+// necessary.
+func NewMediaCache(dir string) (*MediaCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("agentparse: create cache dir: %w", err)
+	}
+	return &MediaCache{dir: dir, client: &http.Client{Timeout: fetchTimeout}}, nil
+}
+
+// path returns the on-disk path a URL would be cached at.
+func (c *MediaCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
+
+// Fetch returns the local path to url's cached contents, downloading it
+// first if it isn't already cached.
+func (c *MediaCache) Fetch(url string) (string, error) {
+	path := c.path(url)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	resp, err := c.client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("agentparse: fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("agentparse: fetch %s: unexpected status %s", url, resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "download-*")
+	if err != nil {
+		return "", fmt.Errorf("agentparse: create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, resp.Body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("agentparse: write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("agentparse: close cache file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("agentparse: rename cache file: %w", err)
+	}
+
+	return path, nil
+}
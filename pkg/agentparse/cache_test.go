@@ -0,0 +1,93 @@
+package agentparse
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMediaCacheFetchCachesOnDisk(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("image-bytes"))
+	}))
+	defer srv.Close()
+
+	cache, err := NewMediaCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewMediaCache: %v", err)
+	}
+
+	path1, err := cache.Fetch(srv.URL)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	data, err := os.ReadFile(path1)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if string(data) != "image-bytes" {
+		t.Errorf("cached contents = %q, want %q", data, "image-bytes")
+	}
+
+	path2, err := cache.Fetch(srv.URL)
+	if err != nil {
+		t.Fatalf("second Fetch: %v", err)
+	}
+	if path2 != path1 {
+		t.Errorf("second Fetch path = %q, want same path %q", path2, path1)
+	}
+	if hits != 1 {
+		t.Errorf("origin hit %d times, want 1 (second Fetch should be served from cache)", hits)
+	}
+}
+
+func TestMediaCacheFetchTimesOutOnSlowOrigin(t *testing.T) {
+	block := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	// Unblock the handler before srv.Close() waits on it, and close the
+	// server before deferred cleanup runs.
+	defer srv.Close()
+	defer close(block)
+
+	cache, err := NewMediaCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewMediaCache: %v", err)
+	}
+
+	originalTimeout := fetchTimeout
+	fetchTimeout = 50 * time.Millisecond
+	defer func() { fetchTimeout = originalTimeout }()
+	cache.client.Timeout = fetchTimeout
+
+	start := time.Now()
+	_, err = cache.Fetch(srv.URL)
+	if err == nil {
+		t.Fatal("Fetch: want timeout error, got nil")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Fetch took %v, want it to give up near the %v timeout", elapsed, fetchTimeout)
+	}
+}
+
+func TestMediaCacheFetchUnexpectedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	cache, err := NewMediaCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewMediaCache: %v", err)
+	}
+
+	if _, err := cache.Fetch(srv.URL); err == nil {
+		t.Fatal("Fetch: want error for a non-200 response, got nil")
+	}
+}
@@ -0,0 +1,100 @@
+package agentparse
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+	_ "modernc.org/sqlite"
+)
+
+// EncodeJSON writes agents to w as indented JSON. HTML escaping is
+// disabled so "&" in agent/media URLs isn't rewritten to "&".
+func EncodeJSON(w io.Writer, agents Agents) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(agents)
+}
+
+// EncodeYAML writes agents to w as YAML.
+func EncodeYAML(w io.Writer, agents Agents) error {
+	encoder := yaml.NewEncoder(w)
+	defer encoder.Close()
+	return encoder.Encode(agents)
+}
+
+// sqliteSchema creates the agents/abilities tables. Abilities live in
+// their own table (one row per ability) rather than a JSON column so the
+// output can be queried directly with plain SQL.
+const sqliteSchema = `
+CREATE TABLE agents (
+	name TEXT PRIMARY KEY,
+	role TEXT,
+	markdown TEXT
+);
+CREATE TABLE media (
+	agent_name TEXT NOT NULL REFERENCES agents(name),
+	position   INTEGER NOT NULL,
+	url        TEXT NOT NULL
+);
+CREATE TABLE abilities (
+	agent_name  TEXT NOT NULL REFERENCES agents(name),
+	position    INTEGER NOT NULL,
+	name        TEXT,
+	description TEXT,
+	image       TEXT
+);
+`
+
+// WriteSQLite renders agents into a fresh SQLite database at path,
+// overwriting any existing file. Unlike EncodeJSON/EncodeYAML this can't
+// stream to an arbitrary io.Writer because the sqlite driver owns the
+// file directly.
+func WriteSQLite(path string, agents Agents) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("agentparse: remove existing %s: %w", path, err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("agentparse: open sqlite db: %w", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("agentparse: create schema: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("agentparse: begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, agent := range agents.Agents {
+		if _, err := tx.Exec(`INSERT INTO agents (name, role, markdown) VALUES (?, ?, ?)`,
+			agent.Name, agent.Role, agent.Markdown); err != nil {
+			return fmt.Errorf("agentparse: insert agent %s: %w", agent.Name, err)
+		}
+
+		for i, media := range agent.Media {
+			if _, err := tx.Exec(`INSERT INTO media (agent_name, position, url) VALUES (?, ?, ?)`,
+				agent.Name, i, media); err != nil {
+				return fmt.Errorf("agentparse: insert media for %s: %w", agent.Name, err)
+			}
+		}
+
+		for i, ability := range agent.Abilities {
+			if _, err := tx.Exec(`INSERT INTO abilities (agent_name, position, name, description, image) VALUES (?, ?, ?, ?, ?)`,
+				agent.Name, i, ability.Name, ability.Description, ability.Image); err != nil {
+				return fmt.Errorf("agentparse: insert ability for %s: %w", agent.Name, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,98 @@
+package agentparse
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func testAgent() Agents {
+	return Agents{Agents: map[string]Agent{
+		"Sage": {
+			Name: "Sage",
+			Role: "Sentinel",
+			Abilities: []Ability{
+				{Name: "Barrier Orb", Description: "Creates a wall.", Image: "http://example.com/orb.png"},
+			},
+			Media:    []string{"http://example.com/sage.png"},
+			Markdown: "# Sage\n",
+		},
+	}}
+}
+
+func TestEncodeJSONRoundTrip(t *testing.T) {
+	want := testAgent()
+
+	var buf bytes.Buffer
+	if err := EncodeJSON(&buf, want); err != nil {
+		t.Fatalf("EncodeJSON: %v", err)
+	}
+
+	var got Agents
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if got.Agents["Sage"].Role != "Sentinel" {
+		t.Errorf("Role = %q, want %q", got.Agents["Sage"].Role, "Sentinel")
+	}
+	if len(got.Agents["Sage"].Abilities) != 1 {
+		t.Errorf("len(Abilities) = %d, want 1", len(got.Agents["Sage"].Abilities))
+	}
+}
+
+func TestEncodeYAMLRoundTrip(t *testing.T) {
+	want := testAgent()
+
+	var buf bytes.Buffer
+	if err := EncodeYAML(&buf, want); err != nil {
+		t.Fatalf("EncodeYAML: %v", err)
+	}
+
+	var got Agents
+	if err := yaml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("yaml.Unmarshal: %v", err)
+	}
+	if got.Agents["Sage"].Role != "Sentinel" {
+		t.Errorf("Role = %q, want %q", got.Agents["Sage"].Role, "Sentinel")
+	}
+}
+
+func TestWriteSQLiteRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.db")
+	agents := testAgent()
+
+	if err := WriteSQLite(path, agents); err != nil {
+		t.Fatalf("WriteSQLite: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	var role string
+	if err := db.QueryRow(`SELECT role FROM agents WHERE name = ?`, "Sage").Scan(&role); err != nil {
+		t.Fatalf("query agents: %v", err)
+	}
+	if role != "Sentinel" {
+		t.Errorf("role = %q, want %q", role, "Sentinel")
+	}
+
+	var abilityName string
+	if err := db.QueryRow(`SELECT name FROM abilities WHERE agent_name = ?`, "Sage").Scan(&abilityName); err != nil {
+		t.Fatalf("query abilities: %v", err)
+	}
+	if abilityName != "Barrier Orb" {
+		t.Errorf("ability name = %q, want %q", abilityName, "Barrier Orb")
+	}
+
+	// WriteSQLite must overwrite rather than fail on an existing file.
+	if err := WriteSQLite(path, agents); err != nil {
+		t.Fatalf("second WriteSQLite: %v", err)
+	}
+}
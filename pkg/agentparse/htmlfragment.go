@@ -0,0 +1,146 @@
+package agentparse
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// htmlFragment is the result of walking a chunk of raw HTML embedded in
+// markdown (Riot's exports mix in <img>, <figure>, and <a> tags).
+type htmlFragment struct {
+	text   string
+	images []string
+}
+
+// isAllowedURL reports whether rawURL uses a scheme in opts.AllowedSchemes
+// (case-insensitive), or is a same-document/relative reference with no
+// scheme at all.
+func isAllowedURL(rawURL string, opts ParserOptions) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if scheme == "" {
+		return true
+	}
+	for _, allowed := range opts.schemes() {
+		if scheme == allowed {
+			return true
+		}
+		if scheme == "data" && allowed == "data:image" && strings.HasPrefix(u.Opaque, "image/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseHTMLFragment parses raw HTML and walks the resulting node tree,
+// collecting <img> sources into images and building text from the visible
+// content of every other node (so <a href="...">text</a> contributes
+// "text" the same way the markdown link replacement does). Entities are
+// decoded via html.UnescapeString along the way.
+func parseHTMLFragment(raw string, opts ParserOptions) htmlFragment {
+	nodes, err := html.ParseFragment(strings.NewReader(raw), &html.Node{
+		Type:     html.ElementNode,
+		Data:     "body",
+		DataAtom: atom.Body,
+	})
+	if err != nil {
+		return htmlFragment{}
+	}
+
+	var frag htmlFragment
+	var sb strings.Builder
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			sb.WriteString(html.UnescapeString(n.Data))
+			return
+		}
+
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Img:
+				if src := htmlAttr(n, "src"); src != "" && isAllowedURL(src, opts) {
+					frag.images = append(frag.images, src)
+					if sb.Len() == 0 {
+						if alt := htmlAttr(n, "alt"); alt != "" {
+							sb.WriteString(html.UnescapeString(alt))
+						}
+					}
+				}
+				return
+			case atom.A:
+				if href := htmlAttr(n, "href"); href != "" && !isAllowedURL(href, opts) {
+					// Disallowed scheme (e.g. javascript:): drop the link,
+					// but still walk into its children for the visible text.
+				}
+			}
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	for _, n := range nodes {
+		walk(n)
+	}
+
+	frag.text = strings.TrimSpace(sb.String())
+	return frag
+}
+
+// htmlAttr returns the value of the first attribute named key on n.
+func htmlAttr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// rawHTMLSource collects the literal bytes of every RawHTML (inline) and
+// HTMLBlock node under n, concatenated in document order.
+func rawHTMLSource(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch t := c.(type) {
+		case *ast.RawHTML:
+			for i := 0; i < t.Segments.Len(); i++ {
+				segment := t.Segments.At(i)
+				sb.Write(segment.Value(source))
+			}
+		case *ast.HTMLBlock:
+			for i := 0; i < t.Lines().Len(); i++ {
+				segment := t.Lines().At(i)
+				sb.Write(segment.Value(source))
+			}
+			if t.HasClosure() {
+				closure := t.ClosureLine
+				sb.Write(closure.Value(source))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return sb.String()
+}
+
+// embeddedHTML extracts the htmlFragment (images + fallback text) from any
+// raw HTML found under n.
+func embeddedHTML(n ast.Node, source []byte, opts ParserOptions) htmlFragment {
+	raw := rawHTMLSource(n, source)
+	if raw == "" {
+		return htmlFragment{}
+	}
+	return parseHTMLFragment(raw, opts)
+}
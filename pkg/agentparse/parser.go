@@ -0,0 +1,130 @@
+package agentparse
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// defaultAllowedSchemes are the URL schemes treated as safe to surface in
+// Agent.Media and Ability.Image when no ParserOptions.AllowedSchemes is
+// given.
+var defaultAllowedSchemes = []string{"http", "https", "data:image"}
+
+// ParserOptions configures a Parser. The AST walk itself (heading/list
+// matching) isn't configurable; what's left after moving off the old
+// regex-based parser is the URL scheme allowlist used when deciding
+// whether to keep a link or image, and the Renderer that turns markdown
+// into an Agent.
+type ParserOptions struct {
+	// AllowedSchemes lists the URL schemes (lowercase, e.g. "http",
+	// "https", "data:image") that Media/Image extraction will keep.
+	// Defaults to http, https, and image data: URLs.
+	AllowedSchemes []string
+
+	// Renderer turns agent markdown into an Agent. Defaults to the
+	// built-in goldmark AST walker.
+	Renderer Renderer
+}
+
+func (o ParserOptions) schemes() []string {
+	if len(o.AllowedSchemes) == 0 {
+		return defaultAllowedSchemes
+	}
+	return o.AllowedSchemes
+}
+
+// Parser reads Riot's exported agent JSON (a "markdown" field alongside
+// arbitrary metadata) and renders it into Agent values.
+type Parser struct {
+	opts     ParserOptions
+	renderer Renderer
+}
+
+// NewParser builds a Parser from opts, filling in the default goldmark
+// Renderer and URL scheme allowlist when left unset.
+func NewParser(opts ParserOptions) *Parser {
+	renderer := opts.Renderer
+	if renderer == nil {
+		renderer = goldmarkRenderer{opts: opts}
+	}
+	return &Parser{opts: opts, renderer: renderer}
+}
+
+// ParseReader reads a single AgentFile JSON document from r and renders it
+// into an Agent.
+func (p *Parser) ParseReader(r io.Reader) (Agent, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Agent{}, fmt.Errorf("agentparse: read: %w", err)
+	}
+
+	var agentFile AgentFile
+	if err := json.Unmarshal(data, &agentFile); err != nil {
+		return Agent{}, fmt.Errorf("agentparse: decode: %w", err)
+	}
+
+	agent, err := p.renderer.Render(agentFile.Markdown)
+	if err != nil {
+		return Agent{}, fmt.Errorf("agentparse: render: %w", err)
+	}
+	return agent, nil
+}
+
+// ParseFile opens path and parses it as a single AgentFile JSON document.
+func (p *Parser) ParseFile(path string) (Agent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Agent{}, fmt.Errorf("agentparse: open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	agent, err := p.ParseReader(f)
+	if err != nil {
+		return Agent{}, fmt.Errorf("agentparse: %s: %w", path, err)
+	}
+	return agent, nil
+}
+
+// ParseDir walks root within fsys for *.json AgentFile documents and
+// parses each one into an Agent, keyed by Agent.Name. Files that fail to
+// read or parse are skipped with an error logged to stderr, matching the
+// tolerant behavior the original one-shot script had.
+func (p *Parser) ParseDir(fsys fs.FS, root string) (Agents, error) {
+	agents := Agents{Agents: make(map[string]Agent)}
+
+	entries, err := fs.ReadDir(fsys, root)
+	if err != nil {
+		return Agents{}, fmt.Errorf("agentparse: read dir %s: %w", root, err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+
+		filePath := path.Join(root, entry.Name())
+		f, err := fsys.Open(filePath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Error reading %s: %v\n", filePath, err)
+			continue
+		}
+
+		agent, err := p.ParseReader(f)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Error parsing %s: %v\n", filePath, err)
+			continue
+		}
+
+		if agent.Name != "" {
+			agents.Agents[agent.Name] = agent
+		}
+	}
+
+	return agents, nil
+}
@@ -0,0 +1,201 @@
+package agentparse
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+	"golang.org/x/net/html"
+	"net/url"
+)
+
+// Renderer translates raw agent markdown into an Agent. It is pluggable so
+// the AgentFile -> Agent translation can be swapped or tested on its own,
+// independent of how the markdown was read.
+type Renderer interface {
+	Render(markdown string) (Agent, error)
+}
+
+// goldmarkRenderer is the default Renderer. It walks the goldmark AST: the
+// first level-1 heading becomes the agent name, a "ROLE" heading followed
+// by a paragraph becomes the role, a "SPECIAL ABILITIES" heading introduces
+// the ability section, and every Image (markdown or raw <img>) is collected
+// into Media.
+type goldmarkRenderer struct {
+	opts ParserOptions
+}
+
+func (r goldmarkRenderer) Render(markdown string) (Agent, error) {
+	agent := Agent{
+		Markdown: markdown,
+	}
+
+	source := []byte(markdown)
+	doc := goldmark.DefaultParser().Parse(text.NewReader(source))
+
+	for child := doc.FirstChild(); child != nil; child = child.NextSibling() {
+		heading, ok := child.(*ast.Heading)
+		if !ok {
+			continue
+		}
+
+		headingText := strings.TrimSpace(nodeText(heading, source))
+
+		switch {
+		case heading.Level == 1 && agent.Name == "":
+			agent.Name = headingText
+		case strings.EqualFold(headingText, "ROLE"):
+			if p := child.NextSibling(); p != nil {
+				agent.Role = strings.TrimSpace(nodeText(p, source))
+			}
+		case strings.EqualFold(headingText, "SPECIAL ABILITIES"):
+			agent.Abilities = r.parseAbilities(child, source)
+		}
+	}
+
+	agent.Media = r.extractImages(doc, source)
+
+	return agent, nil
+}
+
+// nodeText concatenates the text of every Text node under n, decoding
+// HTML entities (e.g. &amp;, &#39;) so they render correctly downstream.
+func nodeText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			if t, ok := c.(*ast.Text); ok {
+				sb.Write(t.Segment.Value(source))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return html.UnescapeString(sb.String())
+}
+
+// findImage returns the first Image node under n, or nil.
+func findImage(n ast.Node) *ast.Image {
+	var image *ast.Image
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			if img, ok := c.(*ast.Image); ok && image == nil {
+				image = img
+				return ast.WalkStop, nil
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return image
+}
+
+// extractImages walks the whole document and collects every image
+// destination, URL-encoded and de-duplicated, in document order. This
+// includes both markdown ![]() images and raw <img> tags mixed into the
+// exported markdown.
+func (r goldmarkRenderer) extractImages(doc ast.Node, source []byte) []string {
+	images := make([]string, 0)
+	seen := make(map[string]bool)
+
+	add := func(rawURL string) {
+		rawURL = strings.TrimSpace(rawURL)
+		if rawURL != "" && !seen[rawURL] {
+			images = append(images, urlEncode(rawURL))
+			seen[rawURL] = true
+		}
+	}
+
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			if img, ok := n.(*ast.Image); ok {
+				add(string(img.Destination))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	for _, src := range embeddedHTML(doc, source, r.opts).images {
+		add(src)
+	}
+
+	return images
+}
+
+// parseAbilities walks the siblings following the "SPECIAL ABILITIES"
+// heading. The first list found holds the numbered ability images; each
+// subsequent Heading (or single-Emphasis paragraph) is treated as an
+// ability name, with the paragraph that follows it as the description.
+func (r goldmarkRenderer) parseAbilities(heading ast.Node, source []byte) []Ability {
+	abilities := []Ability{}
+	imageMap := map[int]string{}
+
+	n := heading.NextSibling()
+
+	if list, ok := n.(*ast.List); ok {
+		i := 1
+		for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+			if img := findImage(item); img != nil {
+				imageMap[i] = string(img.Destination)
+			}
+			i++
+		}
+		n = n.NextSibling()
+	}
+
+	var pendingName string
+	abilityIndex := 0
+
+	for n != nil && abilityIndex < 4 {
+		switch node := n.(type) {
+		case *ast.Heading:
+			pendingName = strings.TrimSpace(nodeText(node, source))
+		case *ast.Paragraph:
+			nodeTxt := strings.TrimSpace(nodeText(node, source))
+			if pendingName == "" && isEmphasisOnly(node) {
+				pendingName = nodeTxt
+			} else if pendingName != "" {
+				description := cleanText(nodeTxt)
+				if description == "" {
+					// No markdown text in this paragraph; fall back to the
+					// alt text of any raw <img> tag it contains.
+					description = cleanText(embeddedHTML(node, source, r.opts).text)
+				}
+				abilityIndex++
+				abilities = append(abilities, Ability{
+					Name:        pendingName,
+					Description: description,
+					Image:       urlEncode(imageMap[abilityIndex]),
+				})
+				pendingName = ""
+			}
+		}
+		n = n.NextSibling()
+	}
+
+	return abilities
+}
+
+// isEmphasisOnly reports whether p's only child is an Emphasis node.
+func isEmphasisOnly(p *ast.Paragraph) bool {
+	c := p.FirstChild()
+	return c != nil && c.NextSibling() == nil && c.Kind() == ast.KindEmphasis
+}
+
+// urlEncode validates and normalizes a URL but keeps & characters intact.
+// Callers are expected to encode with HTML-escaping disabled so & remains
+// as-is in the JSON output.
+func urlEncode(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		// If parsing fails, return the original URL
+		return rawURL
+	}
+	return parsedURL.String()
+}
+
+// cleanText collapses runs of whitespace produced by wrapped markdown
+// paragraphs into single spaces.
+func cleanText(s string) string {
+	return strings.TrimSpace(regexp.MustCompile(`\s+`).ReplaceAllString(s, " "))
+}
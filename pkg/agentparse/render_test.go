@@ -0,0 +1,132 @@
+package agentparse
+
+import "testing"
+
+func TestRenderHeadingAbilities(t *testing.T) {
+	markdown := `# Raze
+
+## ROLE
+
+Duelist
+
+## SPECIAL ABILITIES
+
+1. ![](http://example.com/boombot.png)
+
+### Boom Bot
+
+A remote controlled robot that explodes on contact.
+`
+
+	agent, err := goldmarkRenderer{}.Render(markdown)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if agent.Name != "Raze" {
+		t.Errorf("Name = %q, want %q", agent.Name, "Raze")
+	}
+	if agent.Role != "Duelist" {
+		t.Errorf("Role = %q, want %q", agent.Role, "Duelist")
+	}
+	if len(agent.Abilities) != 1 {
+		t.Fatalf("len(Abilities) = %d, want 1", len(agent.Abilities))
+	}
+	got := agent.Abilities[0]
+	want := Ability{
+		Name:        "Boom Bot",
+		Description: "A remote controlled robot that explodes on contact.",
+		Image:       "http://example.com/boombot.png",
+	}
+	if got != want {
+		t.Errorf("Abilities[0] = %+v, want %+v", got, want)
+	}
+}
+
+func TestRenderEmphasisNamedAbility(t *testing.T) {
+	markdown := `# Raze
+
+## SPECIAL ABILITIES
+
+1. ![](http://example.com/boombot.png)
+
+*Boom Bot*
+
+A remote controlled robot that explodes on contact.
+`
+
+	agent, err := goldmarkRenderer{}.Render(markdown)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if len(agent.Abilities) != 1 {
+		t.Fatalf("len(Abilities) = %d, want 1", len(agent.Abilities))
+	}
+	got := agent.Abilities[0]
+	if got.Name != "Boom Bot" {
+		t.Errorf("Name = %q, want %q", got.Name, "Boom Bot")
+	}
+	if got.Description != "A remote controlled robot that explodes on contact." {
+		t.Errorf("Description = %q, should be the paragraph following the name, not the name itself", got.Description)
+	}
+}
+
+func TestRenderMissingRoleSection(t *testing.T) {
+	markdown := `# Raze
+
+## SPECIAL ABILITIES
+
+### Boom Bot
+
+A remote controlled robot that explodes on contact.
+`
+
+	agent, err := goldmarkRenderer{}.Render(markdown)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if agent.Name != "Raze" {
+		t.Errorf("Name = %q, want %q", agent.Name, "Raze")
+	}
+	if agent.Role != "" {
+		t.Errorf("Role = %q, want empty when no ROLE section is present", agent.Role)
+	}
+}
+
+func TestRenderHTMLImgAltTextFallback(t *testing.T) {
+	markdown := `# Raze
+
+## SPECIAL ABILITIES
+
+1. ![](http://example.com/boombot.png)
+
+### Boom Bot
+
+<br><img src="http://example.com/boombot-description.png" alt="A remote controlled robot that explodes on contact.">
+`
+
+	agent, err := goldmarkRenderer{}.Render(markdown)
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	if len(agent.Abilities) != 1 {
+		t.Fatalf("len(Abilities) = %d, want 1", len(agent.Abilities))
+	}
+	got := agent.Abilities[0]
+	if got.Description != "A remote controlled robot that explodes on contact." {
+		t.Errorf("Description = %q, want the <img> alt text used as a fallback", got.Description)
+	}
+
+	found := false
+	for _, m := range agent.Media {
+		if m == "http://example.com/boombot-description.png" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Media = %v, want it to include the raw <img> src", agent.Media)
+	}
+}
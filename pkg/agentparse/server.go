@@ -0,0 +1,123 @@
+package agentparse
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Server exposes a parsed Agents set over HTTP, proxying media images
+// through a MediaCache so frontends never hotlink Riot's CDN directly.
+type Server struct {
+	Agents Agents
+	Cache  *MediaCache
+}
+
+// NewServer returns a Server ready to be handed to http.ListenAndServe.
+func NewServer(agents Agents, cache *MediaCache) *Server {
+	return &Server{Agents: agents, Cache: cache}
+}
+
+// Handler builds the mux for GET /agents, GET /agents/{name}, and
+// GET /agents/{name}/media/{n}.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/agents", s.handleList)
+	mux.HandleFunc("/agents/", s.handleAgent)
+	return mux
+}
+
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := EncodeJSON(w, s.Agents); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// handleAgent dispatches /agents/{name} and /agents/{name}/media/{n}.
+func (s *Server) handleAgent(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/agents/"), "/"), "/")
+	name := parts[0]
+	agent, ok := s.Agents.Agents[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch len(parts) {
+	case 1:
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(agent); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	case 3:
+		if parts[1] != "media" {
+			http.NotFound(w, r)
+			return
+		}
+		s.serveMedia(w, r, agent, parts[2])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// agentMedia returns every image URL associated with agent, Agent.Media
+// first followed by each ability's Image, so a single index can address
+// either.
+func agentMedia(agent Agent) []string {
+	urls := append([]string{}, agent.Media...)
+	for _, ability := range agent.Abilities {
+		if ability.Image != "" {
+			urls = append(urls, ability.Image)
+		}
+	}
+	return urls
+}
+
+func (s *Server) serveMedia(w http.ResponseWriter, r *http.Request, agent Agent, indexParam string) {
+	n, err := strconv.Atoi(indexParam)
+	if err != nil {
+		http.Error(w, "invalid media index", http.StatusBadRequest)
+		return
+	}
+
+	urls := agentMedia(agent)
+	if n < 0 || n >= len(urls) {
+		http.NotFound(w, r)
+		return
+	}
+
+	path, err := s.Cache.Fetch(urls[n])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// http.ServeContent honors If-Modified-Since using stat.ModTime(),
+	// so repeated requests for an already-cached image are cheap.
+	http.ServeContent(w, r, path, stat.ModTime(), f)
+}
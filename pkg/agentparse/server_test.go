@@ -0,0 +1,139 @@
+package agentparse
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func testAgents(mediaURL string) Agents {
+	return Agents{Agents: map[string]Agent{
+		"Sage": {
+			Name: "Sage",
+			Role: "Sentinel",
+			Abilities: []Ability{
+				{Name: "Barrier Orb", Description: "Creates a wall.", Image: mediaURL},
+			},
+			Media: []string{mediaURL},
+		},
+	}}
+}
+
+func newTestServer(t *testing.T, mediaURL string) *httptest.Server {
+	t.Helper()
+	cache, err := NewMediaCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewMediaCache: %v", err)
+	}
+	server := NewServer(testAgents(mediaURL), cache)
+	return httptest.NewServer(server.Handler())
+}
+
+func TestServerListAgents(t *testing.T) {
+	srv := newTestServer(t, "http://example.invalid/img.png")
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/agents")
+	if err != nil {
+		t.Fatalf("GET /agents: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var got Agents
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := got.Agents["Sage"]; !ok {
+		t.Errorf("Agents = %+v, want it to contain Sage", got.Agents)
+	}
+}
+
+func TestServerGetAgent(t *testing.T) {
+	srv := newTestServer(t, "http://example.invalid/img.png")
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/agents/Sage")
+	if err != nil {
+		t.Fatalf("GET /agents/Sage: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	var got Agent
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Name != "Sage" {
+		t.Errorf("Name = %q, want %q", got.Name, "Sage")
+	}
+}
+
+func TestServerGetAgentNotFound(t *testing.T) {
+	srv := newTestServer(t, "http://example.invalid/img.png")
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/agents/Nobody")
+	if err != nil {
+		t.Fatalf("GET /agents/Nobody: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+}
+
+func TestServerGetMedia(t *testing.T) {
+	imgSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("image-bytes"))
+	}))
+	defer imgSrv.Close()
+
+	srv := newTestServer(t, imgSrv.URL)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/agents/Sage/media/0")
+	if err != nil {
+		t.Fatalf("GET media/0: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+}
+
+func TestServerGetMediaBadIndex(t *testing.T) {
+	srv := newTestServer(t, "http://example.invalid/img.png")
+	defer srv.Close()
+
+	cases := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"non-numeric", "/agents/Sage/media/abc", http.StatusBadRequest},
+		{"out of range", "/agents/Sage/media/99", http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tc.path)
+			if err != nil {
+				t.Fatalf("GET %s: %v", tc.path, err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tc.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tc.want)
+			}
+		})
+	}
+}